@@ -25,14 +25,45 @@ func (e wrapperError) Error() string {
 	return e.msg
 }
 
+// Unwrap returns the root error,
+// so that the standard library's errors.Is and errors.As
+// see through any amount of wrapping done by this package.
+func (e wrapperError) Unwrap() error {
+	return e.root
+}
+
 // Root returns the original error that was wrapped by one or more
 // calls to Wrap. If e does not wrap other errors, it will be returned
 // as-is.
+// Like Detail and Data, Root looks through wrapping done by other
+// packages, such as with fmt.Errorf and %w, including between
+// layers of chain errors.
 func Root(e error) error {
-	if wErr, ok := e.(wrapperError); ok {
-		return wErr.root
+	for {
+		wErr, ok := findWrapper(e)
+		if !ok {
+			return e
+		}
+		e = wErr.root
 	}
-	return e
+}
+
+// findWrapper returns the first wrapperError in err's chain,
+// following Unwrap methods of errors from other packages.
+func findWrapper(err error) (wrapperError, bool) {
+	for err != nil {
+		if wErr, ok := err.(wrapperError); ok {
+			return wErr, true
+		}
+		u, ok := err.(interface {
+			Unwrap() error
+		})
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return wrapperError{}, false
 }
 
 // wrap adds a context message and stack trace to err and returns a new error
@@ -109,12 +140,24 @@ func WithDetailf(err error, format string, v ...interface{}) error {
 	return e1
 }
 
-// Detail returns the detail message contained in err, if any.
+// Detail returns the detail messages contained in err, if any,
+// joined with "; " from innermost to outermost.
 // An error has a detail message if it was made by WithDetail
 // or WithDetailf.
+// Details are collected from every chain error in err,
+// even across wrapping done by another package,
+// such as with fmt.Errorf and %w.
 func Detail(err error) string {
-	wrapper, _ := err.(wrapperError)
-	return strings.Join(wrapper.detail, "; ")
+	var detail []string
+	for {
+		wrapper, ok := findWrapper(err)
+		if !ok {
+			break
+		}
+		detail = append(append([]string(nil), wrapper.detail...), detail...)
+		err = wrapper.root
+	}
+	return strings.Join(detail, "; ")
 }
 
 // withData returns a new error that wraps err
@@ -159,9 +202,19 @@ func WithData(err error, keyval ...interface{}) error {
 }
 
 // Data returns the data item in err, if any.
+// Like Detail, it looks through wrapping done by other packages,
+// and returns the data item of the outermost chain error that has one.
 func Data(err error) map[string]interface{} {
-	wrapper, _ := err.(wrapperError)
-	return wrapper.data
+	for {
+		wrapper, ok := findWrapper(err)
+		if !ok {
+			return nil
+		}
+		if wrapper.data != nil {
+			return wrapper.data
+		}
+		err = wrapper.root
+	}
 }
 
 // Sub returns an error containing root as its root and
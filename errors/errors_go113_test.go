@@ -0,0 +1,93 @@
+// +build go1.13
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type codeError struct{ code string }
+
+func (e *codeError) Error() string { return "code " + e.code }
+
+func TestIsAs(t *testing.T) {
+	root := errors.New("foo")
+	croot := &codeError{"x"}
+	wrappers := []struct {
+		name string
+		f    func(error) error
+	}{
+		{"Wrap", func(err error) error { return Wrap(err) }},
+		{"Wrapf", func(err error) error { return Wrapf(err, "n=%d", 1) }},
+		{"WithDetail", func(err error) error { return WithDetail(err, "bar") }},
+		{"WithDetailf", func(err error) error { return WithDetailf(err, "n=%d", 1) }},
+		{"WithData", func(err error) error { return WithData(err, "a", "b") }},
+		{"Sub", func(err error) error { return Sub(err, Wrap(errors.New("y"), "z")) }},
+		{"nested", func(err error) error { return Wrap(WithDetail(Wrap(err, "a"), "b"), "c") }},
+	}
+
+	for _, w := range wrappers {
+		if err := w.f(root); !errors.Is(err, root) {
+			t.Errorf("%s: errors.Is(%v, %v) = false want true", w.name, err, root)
+		}
+		if err := w.f(root); errors.Is(err, croot) {
+			t.Errorf("%s: errors.Is(%v, %v) = true want false", w.name, err, croot)
+		}
+
+		var got *codeError
+		if err := w.f(croot); !errors.As(err, &got) || got != croot {
+			t.Errorf("%s: errors.As(%v) = %v want %v", w.name, err, got, croot)
+		}
+	}
+}
+
+func TestIsSub(t *testing.T) {
+	oldRoot := errors.New("old")
+	newRoot := errors.New("new")
+	err := Sub(newRoot, Wrap(oldRoot, "a"))
+
+	if !errors.Is(err, newRoot) {
+		t.Errorf("errors.Is(%v, %v) = false want true", err, newRoot)
+	}
+	if errors.Is(err, oldRoot) {
+		t.Errorf("errors.Is(%v, %v) = true want false", err, oldRoot)
+	}
+}
+
+func TestForeignWrap(t *testing.T) {
+	root := errors.New("foo")
+	err := fmt.Errorf("qux: %w", WithData(WithDetail(root, "bar"), "a", "b"))
+
+	if got := Root(err); got != root {
+		t.Errorf("Root(%v) = %v want %v", err, got, root)
+	}
+	if got := Detail(err); got != "bar" {
+		t.Errorf("Detail(%v) = %v want bar", err, got)
+	}
+	if got, want := Data(err), map[string]interface{}{"a": "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Data(%v) = %v want %v", err, got, want)
+	}
+
+	outer := WithDetail(Wrap(err, "outer"), "baz")
+	if got := Root(outer); got != root {
+		t.Errorf("Root(%v) = %v want %v", outer, got, root)
+	}
+	if got := Detail(outer); got != "bar; baz" {
+		t.Errorf("Detail(%v) = %v want bar; baz", outer, got)
+	}
+	if got, want := Data(outer), map[string]interface{}{"a": "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Data(%v) = %v want %v", outer, got, want)
+	}
+
+	// An error with no chain error in it is its own root.
+	plain := fmt.Errorf("qux: %w", root)
+	if got := Root(plain); got != plain {
+		t.Errorf("Root(%v) = %v want %v", plain, got, plain)
+	}
+	if got := Root(Wrap(plain, "outer")); got != plain {
+		t.Errorf("Root(Wrap(%v)) = %v want %v", plain, got, plain)
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"encoding/json"
 	"expvar"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
 	"github.com/codahale/hdrhistogram"
+
+	"chain/errors"
 )
 
 // Period is the size of a RotatingLatency bucket.
@@ -21,6 +24,13 @@ var (
 	rotatingLatenciesMu sync.Mutex
 	rotatingLatencies   []*RotatingLatency
 	latencyExpvar       = expvar.NewMap("latency")
+
+	errorCountsMu sync.Mutex // protects creation of entries in errorCounts
+	errorCounts   = expvar.NewMap("errors")
+	successCounts = expvar.NewMap("successes")
+
+	errorKeysMu sync.Mutex // protects errorKeys
+	errorKeys   = make(map[error]string)
 )
 
 // PublishLatency publishes rl as an expvar inside the
@@ -30,6 +40,62 @@ func PublishLatency(key string, rl *RotatingLatency) {
 	latencyExpvar.Set(key, rl)
 }
 
+// RecordError increments the count of failures of the named
+// operation, keyed by the type of errors.Root(err).
+// Roots registered with RegisterErrors are keyed by their
+// message instead, so each one is counted separately.
+// Counts are published inside the global errors map
+// (itself published under the key "errors"), one map per name.
+// RecordError does nothing if err is nil.
+func RecordError(name string, err error) {
+	if err == nil {
+		return
+	}
+	key := errorKey(errors.Root(err))
+
+	errorCountsMu.Lock()
+	m, _ := errorCounts.Get(name).(*expvar.Map)
+	if m == nil {
+		m = new(expvar.Map).Init()
+		errorCounts.Set(name, m)
+	}
+	errorCountsMu.Unlock()
+	m.Add(key, 1)
+}
+
+// RegisterErrors makes RecordError count each of errs under
+// its own message rather than under its type.
+// It is meant for package-level sentinels such as ErrBadValue,
+// which otherwise all share the type of errors.New.
+// Roots are matched against errs by identity.
+func RegisterErrors(errs ...error) {
+	errorKeysMu.Lock()
+	defer errorKeysMu.Unlock()
+	for _, err := range errs {
+		errorKeys[err] = err.Error()
+	}
+}
+
+func errorKey(root error) string {
+	typ := reflect.TypeOf(root)
+	if typ.Comparable() {
+		errorKeysMu.Lock()
+		key, ok := errorKeys[root]
+		errorKeysMu.Unlock()
+		if ok {
+			return key
+		}
+	}
+	return typ.String()
+}
+
+// RecordSuccess increments the count of successes of the named
+// operation, published under the key "successes".
+// Together with RecordError, it gives an error rate per operation.
+func RecordSuccess(name string) {
+	successCounts.Add(name, 1)
+}
+
 // A Latency records information about the aggregate latency
 // of an operation over time.
 // Internally it holds an HDR histogram (to three significant figures)
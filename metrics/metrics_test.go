@@ -2,11 +2,15 @@ package metrics
 
 import (
 	"encoding/json"
+	"expvar"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/codahale/hdrhistogram"
+
+	"chain/errors"
 )
 
 func TestRotString(t *testing.T) {
@@ -62,3 +66,73 @@ func jsonIsEqual(t *testing.T, a, b string) bool {
 
 	return reflect.DeepEqual(av, bv)
 }
+
+type testError struct{}
+
+func (testError) Error() string { return "test" }
+
+var (
+	errTestA = errors.New("test a")
+	errTestB = errors.New("test b")
+	errTestC = errors.New("test c") // not registered
+)
+
+func init() {
+	RegisterErrors(errTestA, errTestB)
+}
+
+// counter returns the current value of key in m,
+// or 0 if there is none.
+func counter(m *expvar.Map, key string) int64 {
+	if m == nil {
+		return 0
+	}
+	v, _ := m.Get(key).(*expvar.Int)
+	if v == nil {
+		return 0
+	}
+	return v.Value()
+}
+
+func TestRecordError(t *testing.T) {
+	const name = "test.op"
+	errCounts := func() *expvar.Map {
+		m, _ := errorCounts.Get(name).(*expvar.Map)
+		return m
+	}
+	const stringType = "*errors.errorString"
+	keys := []string{"metrics.testError", "test a", "test b", stringType}
+	before := make(map[string]int64)
+	for _, k := range keys {
+		before[k] = counter(errCounts(), k)
+	}
+	beforeOK := counter(successCounts, name)
+
+	RecordError(name, errors.Wrap(testError{}, "wrapped"))
+	RecordError(name, testError{})
+	RecordError(name, errors.WithDetail(errTestA, "detail"))
+	RecordError(name, errTestA)
+	RecordError(name, errTestB)
+	RecordError(name, errTestC)
+	RecordError(name, fmt.Errorf("dynamic %d", 1))
+	RecordError(name, fmt.Errorf("dynamic %d", 2))
+	RecordError(name, nil)
+	RecordSuccess(name)
+
+	// Unregistered roots of the same type, whatever their message,
+	// share one key.
+	want := map[string]int64{"metrics.testError": 2, "test a": 2, "test b": 1, stringType: 3}
+	for _, k := range keys {
+		if got := counter(errCounts(), k) - before[k]; got != want[k] {
+			t.Errorf("error count for %q increased by %d want %d", k, got, want[k])
+		}
+	}
+	for _, k := range []string{"test c", "dynamic 1", "dynamic 2"} {
+		if errCounts().Get(k) != nil {
+			t.Errorf("unexpected error count key %q", k)
+		}
+	}
+	if got := counter(successCounts, name) - beforeOK; got != 1 {
+		t.Errorf("success count increased by %d want 1", got)
+	}
+}